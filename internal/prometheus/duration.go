@@ -0,0 +1,46 @@
+package prometheus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultTimeWindow is the time window used when an SLO doesn't set one.
+const defaultTimeWindow = 30 * 24 * time.Hour
+
+// parseTimeWindow parses a time window string into a duration. Apart from
+// the regular Go duration units, it supports a day unit (e.g "7d", "28d",
+// "90d") so SLO specs can declare rolling windows in the unit operators
+// think in. An empty string returns the default 30 days window.
+func parseTimeWindow(window string) (time.Duration, error) {
+	if window == "" {
+		return defaultTimeWindow, nil
+	}
+
+	if days, ok := strings.CutSuffix(window, "d"); ok {
+		d, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid day based time window %q: %w", window, err)
+		}
+
+		duration := time.Duration(d) * 24 * time.Hour
+		if duration <= 0 {
+			return 0, fmt.Errorf("time window %q must be a positive duration", window)
+		}
+
+		return duration, nil
+	}
+
+	d, err := time.ParseDuration(window)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time window %q: %w", window, err)
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("time window %q must be a positive duration", window)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,159 @@
+package prometheus
+
+import "sync"
+
+// SLIPluginRegisteredEvent is published when an SLI plugin becomes available
+// for use, e.g. when it's loaded into a `YAMLSpecLoader`.
+type SLIPluginRegisteredEvent struct {
+	ID string
+}
+
+// SLIPluginInvokedEvent is published every time an SLI plugin has been
+// called to generate an SLI query, regardless of whether it succeeded.
+type SLIPluginInvokedEvent struct {
+	ID         string
+	Meta       map[string]string
+	DurationMS int64
+	Err        error
+}
+
+// SLIPluginFailedEvent is published when an SLI plugin can't be used
+// anymore, e.g. a health check against an external plugin endpoint fails.
+type SLIPluginFailedEvent struct {
+	ID  string
+	Err error
+}
+
+// SLIPluginUnregisteredEvent is published when an SLI plugin stops being
+// available for use.
+type SLIPluginUnregisteredEvent struct {
+	ID string
+}
+
+// SLIPluginEventSubscriber knows how to react to SLI plugin lifecycle
+// events. Implementations must not block, the event bus already fans out
+// events asynchronously, but a subscriber that performs slow work (e.g. a
+// network call) should still offload it so it doesn't pile up.
+type SLIPluginEventSubscriber interface {
+	OnSLIPluginRegistered(SLIPluginRegisteredEvent)
+	OnSLIPluginInvoked(SLIPluginInvokedEvent)
+	OnSLIPluginFailed(SLIPluginFailedEvent)
+	OnSLIPluginUnregistered(SLIPluginUnregisteredEvent)
+}
+
+// SLIPluginEventBus fans out SLI plugin lifecycle events to subscribers,
+// optionally filtered by plugin ID. A zero value bus is ready to use and a
+// nil bus is safe to publish on (it's a no-op), so it can be wired in as an
+// optional dependency.
+type SLIPluginEventBus struct {
+	mu            sync.Mutex
+	subscriptions []sliPluginEventSubscription
+}
+
+type sliPluginEventSubscription struct {
+	pluginID   string // Empty matches every plugin.
+	subscriber SLIPluginEventSubscriber
+}
+
+// NewSLIPluginEventBus returns a ready to use SLI plugin event bus.
+func NewSLIPluginEventBus() *SLIPluginEventBus {
+	return &SLIPluginEventBus{}
+}
+
+// Subscribe registers a subscriber that will receive every event published
+// on the bus. If pluginID is not empty, the subscriber only receives events
+// for that plugin ID.
+func (b *SLIPluginEventBus) Subscribe(pluginID string, subscriber SLIPluginEventSubscriber) {
+	if b == nil || subscriber == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscriptions = append(b.subscriptions, sliPluginEventSubscription{
+		pluginID:   pluginID,
+		subscriber: subscriber,
+	})
+}
+
+func (b *SLIPluginEventBus) subscribersFor(pluginID string) []SLIPluginEventSubscriber {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := make([]SLIPluginEventSubscriber, 0, len(b.subscriptions))
+	for _, s := range b.subscriptions {
+		if s.pluginID == "" || s.pluginID == pluginID {
+			subs = append(subs, s.subscriber)
+		}
+	}
+
+	return subs
+}
+
+// publishRegistered publishes a SLIPluginRegisteredEvent. A nil bus is a no-op.
+func (b *SLIPluginEventBus) publishRegistered(ev SLIPluginRegisteredEvent) {
+	if b == nil {
+		return
+	}
+
+	for _, s := range b.subscribersFor(ev.ID) {
+		s := s
+		go func() {
+			defer recoverSubscriber()
+			s.OnSLIPluginRegistered(ev)
+		}()
+	}
+}
+
+// publishInvoked publishes a SLIPluginInvokedEvent. A nil bus is a no-op.
+func (b *SLIPluginEventBus) publishInvoked(ev SLIPluginInvokedEvent) {
+	if b == nil {
+		return
+	}
+
+	for _, s := range b.subscribersFor(ev.ID) {
+		s := s
+		go func() {
+			defer recoverSubscriber()
+			s.OnSLIPluginInvoked(ev)
+		}()
+	}
+}
+
+// publishFailed publishes a SLIPluginFailedEvent. A nil bus is a no-op.
+func (b *SLIPluginEventBus) publishFailed(ev SLIPluginFailedEvent) {
+	if b == nil {
+		return
+	}
+
+	for _, s := range b.subscribersFor(ev.ID) {
+		s := s
+		go func() {
+			defer recoverSubscriber()
+			s.OnSLIPluginFailed(ev)
+		}()
+	}
+}
+
+// publishUnregistered publishes a SLIPluginUnregisteredEvent. A nil bus is a no-op.
+func (b *SLIPluginEventBus) publishUnregistered(ev SLIPluginUnregisteredEvent) {
+	if b == nil {
+		return
+	}
+
+	for _, s := range b.subscribersFor(ev.ID) {
+		s := s
+		go func() {
+			defer recoverSubscriber()
+			s.OnSLIPluginUnregistered(ev)
+		}()
+	}
+}
+
+// recoverSubscriber stops a panic inside a subscriber callback from
+// crashing the process; a misbehaving subscriber must not be able to take
+// down spec loading.
+func recoverSubscriber() {
+	_ = recover()
+}
@@ -0,0 +1,165 @@
+package prometheus_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/sloth/internal/prometheus"
+)
+
+type recorderSubscriber struct {
+	mu      sync.Mutex
+	invoked []prometheus.SLIPluginInvokedEvent
+}
+
+func (r *recorderSubscriber) OnSLIPluginRegistered(prometheus.SLIPluginRegisteredEvent)     {}
+func (r *recorderSubscriber) OnSLIPluginFailed(prometheus.SLIPluginFailedEvent)             {}
+func (r *recorderSubscriber) OnSLIPluginUnregistered(prometheus.SLIPluginUnregisteredEvent) {}
+func (r *recorderSubscriber) OnSLIPluginInvoked(ev prometheus.SLIPluginInvokedEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.invoked = append(r.invoked, ev)
+}
+
+func (r *recorderSubscriber) events() []prometheus.SLIPluginInvokedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.invoked
+}
+
+func TestSLIPluginEventBusFiltersByPluginID(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := prometheus.NewSLIPluginEventBus()
+	all := &recorderSubscriber{}
+	onlyOther := &recorderSubscriber{}
+	bus.Subscribe("", all)
+	bus.Subscribe("other_plugin", onlyOther)
+
+	plugins := map[string]prometheus.SLIPlugin{
+		"test_plugin": {
+			ID: "test_plugin",
+			Func: func(ctx context.Context, meta, labels, options map[string]string) (string, error) {
+				return "plugin_raw_expr", nil
+			},
+		},
+	}
+	loader := prometheus.NewYAMLSpecLoader(plugins, bus, nil)
+
+	specYaml := `
+service: test-svc
+version: "prometheus/v1"
+slos:
+  - name: "slo"
+    objective: 99
+    sli:
+      plugin:
+        id: test_plugin
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`
+	_, err := loader.LoadSpec(context.TODO(), []byte(specYaml))
+	assert.NoError(err)
+
+	// The fan-out is non-blocking, give subscribers a moment to receive the event.
+	assert.Eventually(func() bool { return len(all.events()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal("test_plugin", all.events()[0].ID)
+	assert.NoError(all.events()[0].Err)
+	assert.Empty(onlyOther.events())
+}
+
+type panickingSubscriber struct{}
+
+func (p *panickingSubscriber) OnSLIPluginRegistered(prometheus.SLIPluginRegisteredEvent)     {}
+func (p *panickingSubscriber) OnSLIPluginFailed(prometheus.SLIPluginFailedEvent)             {}
+func (p *panickingSubscriber) OnSLIPluginUnregistered(prometheus.SLIPluginUnregisteredEvent) {}
+func (p *panickingSubscriber) OnSLIPluginInvoked(prometheus.SLIPluginInvokedEvent) {
+	panic("subscriber exploded")
+}
+
+func TestSLIPluginEventBusRecoversFromSubscriberPanic(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := prometheus.NewSLIPluginEventBus()
+	bus.Subscribe("", &panickingSubscriber{})
+	sub := &recorderSubscriber{}
+	bus.Subscribe("", sub)
+
+	plugins := map[string]prometheus.SLIPlugin{
+		"test_plugin": {
+			ID: "test_plugin",
+			Func: func(ctx context.Context, meta, labels, options map[string]string) (string, error) {
+				return "plugin_raw_expr", nil
+			},
+		},
+	}
+	loader := prometheus.NewYAMLSpecLoader(plugins, bus, nil)
+
+	specYaml := `
+service: test-svc
+version: "prometheus/v1"
+slos:
+  - name: "slo"
+    objective: 99
+    sli:
+      plugin:
+        id: test_plugin
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`
+	_, err := loader.LoadSpec(context.TODO(), []byte(specYaml))
+	assert.NoError(err)
+
+	// A panicking subscriber must not stop other subscribers from receiving the event.
+	assert.Eventually(func() bool { return len(sub.events()) == 1 }, time.Second, time.Millisecond)
+}
+
+func TestSLIPluginEventBusReportsPluginFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := prometheus.NewSLIPluginEventBus()
+	sub := &recorderSubscriber{}
+	bus.Subscribe("test_plugin", sub)
+
+	plugins := map[string]prometheus.SLIPlugin{
+		"test_plugin": {
+			ID: "test_plugin",
+			Func: func(ctx context.Context, meta, labels, options map[string]string) (string, error) {
+				return "", fmt.Errorf("something")
+			},
+		},
+	}
+	loader := prometheus.NewYAMLSpecLoader(plugins, bus, nil)
+
+	specYaml := `
+service: test-svc
+version: "prometheus/v1"
+slos:
+  - name: "slo"
+    objective: 99
+    sli:
+      plugin:
+        id: test_plugin
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`
+	_, err := loader.LoadSpec(context.TODO(), []byte(specYaml))
+	assert.Error(err)
+
+	assert.Eventually(func() bool { return len(sub.events()) == 1 }, time.Second, time.Millisecond)
+	assert.Error(sub.events()[0].Err)
+}
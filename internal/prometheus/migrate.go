@@ -0,0 +1,106 @@
+package prometheus
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+
+	prometheusv1 "github.com/slok/sloth/pkg/prometheus/api/v1"
+	prometheusv2 "github.com/slok/sloth/pkg/prometheus/api/v2"
+)
+
+// MigrateV1ToV2 takes a prometheus/v1 YAML spec and returns the equivalent
+// prometheus/v2 YAML spec, so existing users can upgrade in place. The page
+// and ticket alerts become "page" and "ticket" severity routes; a disabled
+// alert gets no route.
+func MigrateV1ToV2(specBytes []byte) ([]byte, error) {
+	v1Spec := prometheusv1.Spec{}
+	err := yaml.Unmarshal(specBytes, &v1Spec)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal prometheus/v1 spec: %w", err)
+	}
+
+	if v1Spec.Version != prometheusv1.Version {
+		return nil, fmt.Errorf("spec is not a %q spec, got %q", prometheusv1.Version, v1Spec.Version)
+	}
+
+	v2Spec := prometheusv2.Spec{
+		Version: prometheusv2.Version,
+		Service: v1Spec.Service,
+		Labels:  v1Spec.Labels,
+		SLOs:    make([]prometheusv2.SLO, 0, len(v1Spec.SLOs)),
+	}
+
+	for _, slo := range v1Spec.SLOs {
+		v2Spec.SLOs = append(v2Spec.SLOs, migrateV1SLOToV2(slo))
+	}
+
+	out, err := yaml.Marshal(v2Spec)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal prometheus/v2 spec: %w", err)
+	}
+
+	return out, nil
+}
+
+func migrateV1SLOToV2(slo prometheusv1.SLO) prometheusv2.SLO {
+	v2SLO := prometheusv2.SLO{
+		Name:        slo.Name,
+		Description: slo.Description,
+		Labels:      slo.Labels,
+		Objective:   slo.Objective,
+		TimeWindow:  slo.TimeWindow,
+		SLI: prometheusv2.SLI{
+			Events: migrateV1SLIEventsToV2(slo.SLI.Events),
+			Raw:    migrateV1SLIRawToV2(slo.SLI.Raw),
+			Plugin: migrateV1SLIPluginToV2(slo.SLI.Plugin),
+		},
+		Alerting: prometheusv2.Alerting{
+			Name:        slo.Alerting.Name,
+			Labels:      slo.Alerting.Labels,
+			Annotations: slo.Alerting.Annotations,
+		},
+	}
+
+	if !slo.Alerting.PageAlert.Disable {
+		v2SLO.Alerting.Routes = append(v2SLO.Alerting.Routes, prometheusv2.AlertRoute{
+			Severity:    "page",
+			Labels:      slo.Alerting.PageAlert.Labels,
+			Annotations: slo.Alerting.PageAlert.Annotations,
+		})
+	}
+
+	if !slo.Alerting.TicketAlert.Disable {
+		v2SLO.Alerting.Routes = append(v2SLO.Alerting.Routes, prometheusv2.AlertRoute{
+			Severity:    "ticket",
+			Labels:      slo.Alerting.TicketAlert.Labels,
+			Annotations: slo.Alerting.TicketAlert.Annotations,
+		})
+	}
+
+	return v2SLO
+}
+
+func migrateV1SLIEventsToV2(e *prometheusv1.SLIEvents) *prometheusv2.SLIEvents {
+	if e == nil {
+		return nil
+	}
+
+	return &prometheusv2.SLIEvents{ErrorQuery: e.ErrorQuery, TotalQuery: e.TotalQuery}
+}
+
+func migrateV1SLIRawToV2(r *prometheusv1.SLIRaw) *prometheusv2.SLIRaw {
+	if r == nil {
+		return nil
+	}
+
+	return &prometheusv2.SLIRaw{ErrorRatioQuery: r.ErrorRatioQuery}
+}
+
+func migrateV1SLIPluginToV2(p *prometheusv1.SLIPlugin) *prometheusv2.SLIPlugin {
+	if p == nil {
+		return nil
+	}
+
+	return &prometheusv2.SLIPlugin{ID: p.ID, Options: p.Options}
+}
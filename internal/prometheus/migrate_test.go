@@ -0,0 +1,60 @@
+package prometheus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/slok/sloth/internal/prometheus"
+)
+
+func TestMigrateV1ToV2(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	v1Spec := `
+service: test-svc
+version: "prometheus/v1"
+labels:
+  owner: myteam
+slos:
+  - name: "slo1"
+    objective: 99.9
+    time_window: "7d"
+    sli:
+      events:
+        error_query: test_expr_error_1
+        total_query: test_expr_total_1
+    alerting:
+      name: testAlert
+      page_alert:
+        labels:
+          severity: slack
+      ticket_alert:
+        disable: true
+`
+
+	v2Bytes, err := prometheus.MigrateV1ToV2([]byte(v1Spec))
+	require.NoError(err)
+
+	loader := prometheus.NewYAMLSpecLoader(nil, nil, nil)
+	gotModel, err := loader.LoadSpec(context.TODO(), v2Bytes)
+	require.NoError(err)
+
+	require.Len(gotModel.SLOs, 1)
+	slo := gotModel.SLOs[0]
+	assert.Equal("test-svc-slo1", slo.ID)
+	assert.Equal(`test_expr_error_1`, slo.SLI.Events.ErrorQuery)
+	require.Len(slo.AlertRoutes, 1)
+	assert.Equal("page", slo.AlertRoutes[0].Severity)
+	assert.Equal(map[string]string{"severity": "slack"}, slo.AlertRoutes[0].Labels)
+}
+
+func TestMigrateV1ToV2RejectsNonV1Specs(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := prometheus.MigrateV1ToV2([]byte(`version: "prometheus/v2"`))
+	assert.Error(err)
+}
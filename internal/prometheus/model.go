@@ -0,0 +1,95 @@
+package prometheus
+
+import (
+	"context"
+	"time"
+)
+
+// SLOGroup is the group of SLOs.
+type SLOGroup struct {
+	SLOs []SLO
+}
+
+// SLO represents a service level objective configuration.
+type SLO struct {
+	ID          string
+	Name        string
+	Description string
+	Service     string
+	SLI         SLI
+	// TimeWindow is the SLO's rolling evaluation window (e.g 30 days). This
+	// package only loads and validates it; generating the window-suffixed
+	// burn-rate recording rules from it is the job of a rules generator,
+	// which doesn't exist in this tree.
+	TimeWindow      time.Duration
+	Objective       float64
+	Labels          map[string]string
+	PageAlertMeta   AlertMeta
+	TicketAlertMeta AlertMeta
+	// AlertRoutes holds an arbitrary number of alert severities, used by specs
+	// that don't fit the page/ticket pair (e.g the v2 Prometheus spec).
+	AlertRoutes []AlertRoute
+}
+
+// SLI reprensents an SLI and how to calculate the SLI, every SLI
+// has a way it's calculated.
+type SLI struct {
+	Events *SLIEvents
+	Raw    *SLIRaw
+}
+
+// SLIEvents is the way to get the SLI (number of events) using raw queries.
+type SLIEvents struct {
+	ErrorQuery string
+	TotalQuery string
+}
+
+// SLIRaw is the way to get the SLI (percentage of failure) already calculated directly using a Prometheus query.
+type SLIRaw struct {
+	ErrorRatioQuery string
+}
+
+// AlertMeta is the metadata of an alert configuration.
+type AlertMeta struct {
+	Disable     bool
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// AlertRoute is a single, severity-named alert configuration.
+type AlertRoute struct {
+	Severity    string
+	Disable     bool
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// SLIPlugin knows how to return an SLI query based on some options.
+type SLIPlugin struct {
+	ID   string
+	Func SLIPluginFunc
+	// OptionsSchema optionally declares the options this plugin accepts, so
+	// the loader can validate and coerce them before calling Func. A nil
+	// schema accepts any option, stringified as-is.
+	OptionsSchema SLIPluginOptionsSchema
+}
+
+// SLIPluginFunc knows how to generate an SLI (in the form of a raw query) based on the
+// received metadata, the spec labels and the plugin specific options.
+type SLIPluginFunc func(ctx context.Context, meta, labels, options map[string]string) (string, error)
+
+func mergeLabels(labels ...map[string]string) map[string]string {
+	res := map[string]string{}
+	for _, lbs := range labels {
+		for k, v := range lbs {
+			res[k] = v
+		}
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	return res
+}
@@ -0,0 +1,115 @@
+package prometheus
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SLIPluginOptionKind is the expected type of a plugin option value.
+type SLIPluginOptionKind int
+
+const (
+	// SLIPluginOptionKindString is a plain string option.
+	SLIPluginOptionKindString SLIPluginOptionKind = iota
+	// SLIPluginOptionKindBool is a boolean option.
+	SLIPluginOptionKindBool
+	// SLIPluginOptionKindInt is a whole number option.
+	SLIPluginOptionKindInt
+	// SLIPluginOptionKindFloat is a floating point number option.
+	SLIPluginOptionKindFloat
+)
+
+// SLIPluginOption describes a single option an SLIPlugin accepts.
+type SLIPluginOption struct {
+	Kind     SLIPluginOptionKind
+	Required bool
+}
+
+// SLIPluginOptionsSchema declares the options an SLIPlugin accepts, keyed by
+// option name. A nil/empty schema disables validation, options are passed
+// through as-is stringified, keeping plugins that don't declare a schema
+// working the same way they always have.
+type SLIPluginOptionsSchema map[string]SLIPluginOption
+
+// validatePluginOptions checks the YAML-decoded option values of an SLO's
+// plugin against schema and coerces them to the map[string]string
+// SLIPluginFunc expects, rejecting unknown keys, wrong types and missing
+// required options.
+func validatePluginOptions(sloName, pluginID string, schema SLIPluginOptionsSchema, raw map[string]interface{}) (map[string]string, error) {
+	options := make(map[string]string, len(raw))
+
+	if len(schema) == 0 {
+		for k, v := range raw {
+			options[k] = fmt.Sprintf("%v", v)
+		}
+
+		return options, nil
+	}
+
+	for key, value := range raw {
+		opt, ok := schema[key]
+		if !ok {
+			return nil, fmt.Errorf("slo %q: plugin %q: unknown option %q", sloName, pluginID, key)
+		}
+
+		coerced, err := coercePluginOption(opt.Kind, value)
+		if err != nil {
+			return nil, fmt.Errorf("slo %q: plugin %q: option %q: %w", sloName, pluginID, key, err)
+		}
+
+		options[key] = coerced
+	}
+
+	for key, opt := range schema {
+		if !opt.Required {
+			continue
+		}
+
+		if _, ok := raw[key]; !ok {
+			return nil, fmt.Errorf("slo %q: plugin %q: missing required option %q", sloName, pluginID, key)
+		}
+	}
+
+	return options, nil
+}
+
+func coercePluginOption(kind SLIPluginOptionKind, value interface{}) (string, error) {
+	switch kind {
+	case SLIPluginOptionKindString:
+		s, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("expected a string, got %T", value)
+		}
+
+		return s, nil
+
+	case SLIPluginOptionKindBool:
+		b, ok := value.(bool)
+		if !ok {
+			return "", fmt.Errorf("expected a bool, got %T", value)
+		}
+
+		return strconv.FormatBool(b), nil
+
+	case SLIPluginOptionKindInt:
+		i, ok := value.(int)
+		if !ok {
+			return "", fmt.Errorf("expected an int, got %T", value)
+		}
+
+		return strconv.Itoa(i), nil
+
+	case SLIPluginOptionKindFloat:
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case int:
+			return strconv.Itoa(v), nil
+		default:
+			return "", fmt.Errorf("expected a float, got %T", value)
+		}
+
+	default:
+		return "", fmt.Errorf("unknown option kind %d", kind)
+	}
+}
@@ -0,0 +1,165 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	prometheuspluginv1 "github.com/slok/sloth/pkg/prometheus/plugin/v1"
+)
+
+// defaultRemotePluginCallTimeout bounds a single GenerateQuery call when a
+// RemoteSLIPluginConfig doesn't set one.
+const defaultRemotePluginCallTimeout = 2 * time.Second
+
+// RemoteSLIPluginConfig configures an out-of-process SLI plugin endpoint
+// speaking the SLIPluginService gRPC protocol.
+type RemoteSLIPluginConfig struct {
+	// ID is the plugin ID as referenced from SLO specs (`sli.plugin.id`).
+	ID string
+	// Target is the gRPC dial target, e.g "unix:///run/sloth/plugin.sock" or "127.0.0.1:4771".
+	Target string
+	// CallTimeout bounds a single GenerateQuery call. Defaults to 2 seconds.
+	CallTimeout time.Duration
+	// Retries is the number of extra attempts on failure. Defaults to 0 (no retries).
+	Retries int
+}
+
+// SLIPluginRegistry dials out-of-process SLI plugins and exposes them as
+// regular SLIPlugin funcs, so `YAMLSpecLoader` doesn't need to know whether
+// a plugin runs in-process or behind gRPC. It health-checks the endpoints
+// and falls back to an in-process plugin with the same ID if the endpoint
+// is unreachable or a call fails.
+type SLIPluginRegistry struct {
+	mu       sync.RWMutex
+	remotes  map[string]remoteSLIPlugin
+	fallback map[string]SLIPlugin
+	bus      *SLIPluginEventBus
+}
+
+type remoteSLIPlugin struct {
+	cfg    RemoteSLIPluginConfig
+	conn   *grpc.ClientConn
+	client prometheuspluginv1.SLIPluginServiceClient
+}
+
+// NewSLIPluginRegistry dials every configured remote plugin and returns a
+// registry ready to be queried. fallback is used when a remote plugin is
+// unreachable or fails; it can be nil.
+func NewSLIPluginRegistry(configs []RemoteSLIPluginConfig, fallback map[string]SLIPlugin, bus *SLIPluginEventBus) (*SLIPluginRegistry, error) {
+	r := &SLIPluginRegistry{
+		remotes:  make(map[string]remoteSLIPlugin, len(configs)),
+		fallback: fallback,
+		bus:      bus,
+	}
+
+	for _, cfg := range configs {
+		if cfg.CallTimeout <= 0 {
+			cfg.CallTimeout = defaultRemotePluginCallTimeout
+		}
+
+		conn, err := grpc.Dial(cfg.Target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, fmt.Errorf("could not dial SLI plugin %q at %q: %w", cfg.ID, cfg.Target, err)
+		}
+
+		r.remotes[cfg.ID] = remoteSLIPlugin{
+			cfg:    cfg,
+			conn:   conn,
+			client: prometheuspluginv1.NewSLIPluginServiceClient(conn),
+		}
+		r.bus.publishRegistered(SLIPluginRegisteredEvent{ID: cfg.ID})
+	}
+
+	return r, nil
+}
+
+// HealthCheck probes every remote plugin and returns the first error found,
+// publishing a SLIPluginFailedEvent for every endpoint that doesn't
+// respond. It calls the dedicated Ping RPC rather than GenerateQuery, so a
+// reachable plugin that rejects an empty/invalid query request (e.g. a
+// missing required option) isn't misreported as unhealthy.
+func (r *SLIPluginRegistry) HealthCheck(ctx context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var firstErr error
+	for id, rp := range r.remotes {
+		callCtx, cancel := context.WithTimeout(ctx, rp.cfg.CallTimeout)
+		_, err := rp.client.Ping(callCtx, &prometheuspluginv1.PingRequest{})
+		cancel()
+		if err != nil {
+			r.bus.publishFailed(SLIPluginFailedEvent{ID: id, Err: err})
+			if firstErr == nil {
+				firstErr = fmt.Errorf("plugin %q health check failed: %w", id, err)
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// Plugins returns the set of SLIPlugin funcs the registry knows about. Each
+// remote plugin is wrapped so calling it dials out over gRPC (with the
+// configured timeout and retries) and falls back to an in-process plugin
+// with the same ID on failure.
+func (r *SLIPluginRegistry) Plugins() map[string]SLIPlugin {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	plugins := make(map[string]SLIPlugin, len(r.remotes)+len(r.fallback))
+	for id, fb := range r.fallback {
+		plugins[id] = fb
+	}
+
+	for id, rp := range r.remotes {
+		plugins[id] = SLIPlugin{ID: id, Func: r.remoteFunc(rp)}
+	}
+
+	return plugins
+}
+
+func (r *SLIPluginRegistry) remoteFunc(rp remoteSLIPlugin) SLIPluginFunc {
+	fallback, hasFallback := r.fallback[rp.cfg.ID]
+
+	return func(ctx context.Context, meta, labels, options map[string]string) (string, error) {
+		req := &prometheuspluginv1.GenerateQueryRequest{Meta: meta, Labels: labels, Options: options}
+
+		var lastErr error
+		for attempt := 0; attempt <= rp.cfg.Retries; attempt++ {
+			callCtx, cancel := context.WithTimeout(ctx, rp.cfg.CallTimeout)
+			resp, err := rp.client.GenerateQuery(callCtx, req)
+			cancel()
+			if err == nil {
+				return resp.RawQuery, nil
+			}
+			lastErr = err
+		}
+
+		if hasFallback {
+			return fallback.Func(ctx, meta, labels, options)
+		}
+
+		return "", fmt.Errorf("remote SLI plugin %q failed after %d attempt(s) and has no in-process fallback: %w", rp.cfg.ID, rp.cfg.Retries+1, lastErr)
+	}
+}
+
+// Close closes every dialed connection.
+func (r *SLIPluginRegistry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var lastErr error
+	for id, rp := range r.remotes {
+		if err := rp.conn.Close(); err != nil {
+			lastErr = err
+		}
+		r.bus.publishUnregistered(SLIPluginUnregisteredEvent{ID: id})
+	}
+
+	return lastErr
+}
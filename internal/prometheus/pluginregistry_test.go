@@ -0,0 +1,155 @@
+package prometheus_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/slok/sloth/internal/prometheus"
+	prometheuspluginv1 "github.com/slok/sloth/pkg/prometheus/plugin/v1"
+)
+
+type fakeSLIPluginServiceServer struct {
+	prometheuspluginv1.SLIPluginServiceServer
+}
+
+func (fakeSLIPluginServiceServer) GenerateQuery(ctx context.Context, req *prometheuspluginv1.GenerateQueryRequest) (*prometheuspluginv1.GenerateQueryResponse, error) {
+	return &prometheuspluginv1.GenerateQueryResponse{
+		RawQuery: fmt.Sprintf("remote_expr{service=%q,k1=%q}", req.Meta["service"], req.Options["k1"]),
+	}, nil
+}
+
+func (fakeSLIPluginServiceServer) Ping(ctx context.Context, req *prometheuspluginv1.PingRequest) (*prometheuspluginv1.PingResponse, error) {
+	return &prometheuspluginv1.PingResponse{}, nil
+}
+
+// strictSLIPluginServiceServer rejects GenerateQuery calls that don't carry
+// a required option, while still responding to Ping, to exercise that
+// health checks don't conflate business-logic validation with liveness.
+type strictSLIPluginServiceServer struct {
+	prometheuspluginv1.SLIPluginServiceServer
+}
+
+func (strictSLIPluginServiceServer) GenerateQuery(ctx context.Context, req *prometheuspluginv1.GenerateQueryRequest) (*prometheuspluginv1.GenerateQueryResponse, error) {
+	if req.Options["code"] == "" {
+		return nil, fmt.Errorf("missing required option %q", "code")
+	}
+
+	return &prometheuspluginv1.GenerateQueryResponse{RawQuery: "remote_expr"}, nil
+}
+
+func (strictSLIPluginServiceServer) Ping(ctx context.Context, req *prometheuspluginv1.PingRequest) (*prometheuspluginv1.PingResponse, error) {
+	return &prometheuspluginv1.PingResponse{}, nil
+}
+
+func startStrictSLIPluginServiceServer(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	prometheuspluginv1.RegisterSLIPluginServiceServer(srv, strictSLIPluginServiceServer{})
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+func startFakeSLIPluginServiceServer(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	prometheuspluginv1.RegisterSLIPluginServiceServer(srv, fakeSLIPluginServiceServer{})
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+func TestSLIPluginRegistryCallsRemotePlugin(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	addr := startFakeSLIPluginServiceServer(t)
+
+	registry, err := prometheus.NewSLIPluginRegistry([]prometheus.RemoteSLIPluginConfig{
+		{ID: "remote_plugin", Target: addr},
+	}, nil, nil)
+	require.NoError(err)
+	t.Cleanup(func() { _ = registry.Close() })
+
+	plugin, ok := registry.Plugins()["remote_plugin"]
+	require.True(ok)
+
+	query, err := plugin.Func(context.Background(), map[string]string{"service": "svc"}, nil, map[string]string{"k1": "v1"})
+	require.NoError(err)
+	assert.Equal(`remote_expr{service="svc",k1="v1"}`, query)
+}
+
+func TestSLIPluginRegistryFallsBackToInProcessPlugin(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fallback := map[string]prometheus.SLIPlugin{
+		"remote_plugin": {
+			ID: "remote_plugin",
+			Func: func(ctx context.Context, meta, labels, options map[string]string) (string, error) {
+				return "fallback_expr", nil
+			},
+		},
+	}
+
+	registry, err := prometheus.NewSLIPluginRegistry([]prometheus.RemoteSLIPluginConfig{
+		{ID: "remote_plugin", Target: "127.0.0.1:1", CallTimeout: 100 * time.Millisecond},
+	}, fallback, nil)
+	require.NoError(err)
+	t.Cleanup(func() { _ = registry.Close() })
+
+	plugin, ok := registry.Plugins()["remote_plugin"]
+	require.True(ok)
+
+	query, err := plugin.Func(context.Background(), nil, nil, nil)
+	require.NoError(err)
+	assert.Equal("fallback_expr", query)
+}
+
+func TestSLIPluginRegistryHealthCheckReportsUnreachableEndpoints(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	registry, err := prometheus.NewSLIPluginRegistry([]prometheus.RemoteSLIPluginConfig{
+		{ID: "remote_plugin", Target: "127.0.0.1:1", CallTimeout: 100 * time.Millisecond},
+	}, nil, nil)
+	require.NoError(err)
+	t.Cleanup(func() { _ = registry.Close() })
+
+	assert.Error(registry.HealthCheck(context.Background()))
+}
+
+func TestSLIPluginRegistryHealthCheckDoesNotFailOnBusinessValidation(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	addr := startStrictSLIPluginServiceServer(t)
+
+	registry, err := prometheus.NewSLIPluginRegistry([]prometheus.RemoteSLIPluginConfig{
+		{ID: "strict_plugin", Target: addr},
+	}, nil, nil)
+	require.NoError(err)
+	t.Cleanup(func() { _ = registry.Close() })
+
+	// GenerateQuery rejects the call because the required "code" option is
+	// missing, but the endpoint is reachable, so the health check must
+	// still report it healthy.
+	assert.NoError(registry.HealthCheck(context.Background()))
+}
@@ -3,125 +3,84 @@ package prometheus
 import (
 	"context"
 	"fmt"
-	"time"
+	"sort"
 
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"gopkg.in/yaml.v2"
 
 	prometheusv1 "github.com/slok/sloth/pkg/prometheus/api/v1"
-	prometheuspluginv1 "github.com/slok/sloth/pkg/prometheus/plugin/v1"
+	prometheusv2 "github.com/slok/sloth/pkg/prometheus/api/v2"
 )
 
-// YAMLSpecLoader knows how to load YAML specs and converts them to a model.
+// VersionedSpecLoader knows how to load a YAML/JSON spec of a single,
+// specific version into the common model.
+type VersionedSpecLoader interface {
+	LoadSpec(ctx context.Context, data []byte) (*SLOGroup, error)
+}
+
+// YAMLSpecLoader knows how to load YAML specs of any supported version and
+// converts them to a model. It dispatches to the right VersionedSpecLoader
+// based on the spec's `version` field.
 type YAMLSpecLoader struct {
-	plugins map[string]SLIPlugin
+	loaders map[string]VersionedSpecLoader
+	logger  log.Logger
 }
 
-// NewYAMLSpecLoader returns a YAML spec loader.
-func NewYAMLSpecLoader(plugins map[string]SLIPlugin) YAMLSpecLoader {
+// NewYAMLSpecLoader returns a YAML spec loader able to load prometheus/v1
+// and prometheus/v2 specs. pluginBus is optional, a nil bus disables SLI
+// plugin lifecycle events. logger is optional, a nil logger discards every
+// log line.
+func NewYAMLSpecLoader(plugins map[string]SLIPlugin, pluginBus *SLIPluginEventBus, logger log.Logger) YAMLSpecLoader {
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+
+	for id := range plugins {
+		pluginBus.publishRegistered(SLIPluginRegisteredEvent{ID: id})
+	}
+
 	return YAMLSpecLoader{
-		plugins: plugins,
+		loaders: map[string]VersionedSpecLoader{
+			prometheusv1.Version: newYAMLSpecV1Loader(plugins, pluginBus, logger),
+			prometheusv2.Version: newYAMLSpecV2Loader(plugins, pluginBus, logger),
+		},
+		logger: log.With(logger, "component", "prometheus.YAMLSpecLoader"),
 	}
 }
 
+// LoadSpec peeks at the spec's `version` field and dispatches to the
+// VersionedSpecLoader that knows how to load it.
 func (y YAMLSpecLoader) LoadSpec(ctx context.Context, data []byte) (*SLOGroup, error) {
 	if len(data) == 0 {
+		level.Error(y.logger).Log("msg", "spec is empty")
 		return nil, fmt.Errorf("spec is required")
 	}
 
-	s := prometheusv1.Spec{}
-	err := yaml.Unmarshal(data, &s)
+	v := struct {
+		Version string `yaml:"version"`
+	}{}
+	err := yaml.Unmarshal(data, &v)
 	if err != nil {
+		level.Error(y.logger).Log("msg", "could not unmarshal YAML spec", "err", err)
 		return nil, fmt.Errorf("could not unmarshall YAML spec correctly: %w", err)
 	}
 
-	// Check version.
-	if s.Version != prometheusv1.Version {
-		return nil, fmt.Errorf("invalid spec version, should be %q", prometheusv1.Version)
-	}
-
-	// Check at least we have one SLO.
-	if len(s.SLOs) == 0 {
-		return nil, fmt.Errorf("at least one SLO is required")
-	}
-
-	m, err := y.mapSpecToModel(ctx, s)
-	if err != nil {
-		return nil, fmt.Errorf("could not map to model: %w", err)
+	loader, ok := y.loaders[v.Version]
+	if !ok {
+		level.Warn(y.logger).Log("msg", "invalid spec version", "got_version", v.Version, "supported_versions", supportedSpecVersions(y.loaders))
+		return nil, fmt.Errorf("invalid spec version %q, should be one of %v", v.Version, supportedSpecVersions(y.loaders))
 	}
 
-	return m, nil
+	return loader.LoadSpec(ctx, data)
 }
 
-func (y YAMLSpecLoader) mapSpecToModel(ctx context.Context, spec prometheusv1.Spec) (*SLOGroup, error) {
-	models := make([]SLO, 0, len(spec.SLOs))
-	for _, specSLO := range spec.SLOs {
-		slo := SLO{
-			ID:              fmt.Sprintf("%s-%s", spec.Service, specSLO.Name),
-			Name:            specSLO.Name,
-			Description:     specSLO.Description,
-			Service:         spec.Service,
-			TimeWindow:      30 * 24 * time.Hour, // Default and for now the only one supported.
-			Objective:       specSLO.Objective,
-			Labels:          mergeLabels(spec.Labels, specSLO.Labels),
-			PageAlertMeta:   AlertMeta{Disable: true},
-			TicketAlertMeta: AlertMeta{Disable: true},
-		}
-
-		// Set SLIs.
-		if specSLO.SLI.Events != nil {
-			slo.SLI.Events = &SLIEvents{
-				ErrorQuery: specSLO.SLI.Events.ErrorQuery,
-				TotalQuery: specSLO.SLI.Events.TotalQuery,
-			}
-		}
-
-		if specSLO.SLI.Raw != nil {
-			slo.SLI.Raw = &SLIRaw{
-				ErrorRatioQuery: specSLO.SLI.Raw.ErrorRatioQuery,
-			}
-		}
-
-		if specSLO.SLI.Plugin != nil {
-			plugin, ok := y.plugins[specSLO.SLI.Plugin.ID]
-			if !ok {
-				return nil, fmt.Errorf("unknown plugin: %q", specSLO.SLI.Plugin.ID)
-			}
-
-			meta := map[string]string{
-				prometheuspluginv1.SLIPluginMetaService:   spec.Service,
-				prometheuspluginv1.SLIPluginMetaSLO:       specSLO.Name,
-				prometheuspluginv1.SLIPluginMetaObjective: fmt.Sprintf("%f", specSLO.Objective),
-			}
-
-			rawQuery, err := plugin.Func(ctx, meta, spec.Labels, specSLO.SLI.Plugin.Options)
-			if err != nil {
-				return nil, fmt.Errorf("plugin %q execution error: %w", specSLO.SLI.Plugin.ID, err)
-			}
-
-			slo.SLI.Raw = &SLIRaw{
-				ErrorRatioQuery: rawQuery,
-			}
-		}
-
-		// Set alerts.
-		if !specSLO.Alerting.PageAlert.Disable {
-			slo.PageAlertMeta = AlertMeta{
-				Name:        specSLO.Alerting.Name,
-				Labels:      mergeLabels(specSLO.Alerting.Labels, specSLO.Alerting.PageAlert.Labels),
-				Annotations: mergeLabels(specSLO.Alerting.Annotations, specSLO.Alerting.PageAlert.Annotations),
-			}
-		}
-
-		if !specSLO.Alerting.TicketAlert.Disable {
-			slo.TicketAlertMeta = AlertMeta{
-				Name:        specSLO.Alerting.Name,
-				Labels:      mergeLabels(specSLO.Alerting.Labels, specSLO.Alerting.TicketAlert.Labels),
-				Annotations: mergeLabels(specSLO.Alerting.Annotations, specSLO.Alerting.TicketAlert.Annotations),
-			}
-		}
-
-		models = append(models, slo)
+func supportedSpecVersions(loaders map[string]VersionedSpecLoader) []string {
+	vs := make([]string, 0, len(loaders))
+	for v := range loaders {
+		vs = append(vs, v)
 	}
+	sort.Strings(vs)
 
-	return &SLOGroup{SLOs: models}, nil
+	return vs
 }
@@ -40,7 +40,7 @@ slos:
 		"Spec with invalid version should fail.": {
 			specYaml: `
 service: test-svc
-version: "prometheus/v2"
+version: "prometheus/v3"
 slos:
 - name: something
 `,
@@ -56,6 +56,81 @@ slos: []
 			expErr: true,
 		},
 
+		"Spec with an invalid time window should fail.": {
+			specYaml: `
+service: test-svc
+version: "prometheus/v1"
+slos:
+  - name: "slo"
+    objective: 99
+    time_window: "notaduration"
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with a negative time window should fail.": {
+			specYaml: `
+service: test-svc
+version: "prometheus/v1"
+slos:
+  - name: "slo"
+    objective: 99
+    time_window: "-7d"
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with a custom time window should use it instead of the default one.": {
+			specYaml: `
+service: test-svc
+version: "prometheus/v1"
+slos:
+  - name: "slo"
+    objective: 99
+    time_window: "7d"
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo",
+					Name:       "slo",
+					Service:    "test-svc",
+					TimeWindow: 7 * 24 * time.Hour,
+					SLI: prometheus.SLI{
+						Raw: &prometheus.SLIRaw{
+							ErrorRatioQuery: "test_expr_ratio",
+						},
+					},
+					Objective:       99,
+					PageAlertMeta:   prometheus.AlertMeta{Disable: true},
+					TicketAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
 		"Spec without unknown SLI plugin should fail.": {
 			specYaml: `
 service: test-svc
@@ -156,6 +231,120 @@ slos:
 			}},
 		},
 
+		"Spec with SLI plugin options that don't match the plugin's schema should fail.": {
+			plugins: map[string]prometheus.SLIPlugin{
+				"schema_plugin": {
+					ID: "schema_plugin",
+					Func: func(ctx context.Context, meta map[string]string, labels map[string]string, options map[string]string) (string, error) {
+						return "plugin_raw_expr", nil
+					},
+					OptionsSchema: prometheus.SLIPluginOptionsSchema{
+						"threshold": {Kind: prometheus.SLIPluginOptionKindInt, Required: true},
+					},
+				},
+			},
+			specYaml: `
+service: test-svc
+version: "prometheus/v1"
+slos:
+  - name: "slo"
+    objective: 99
+    sli:
+      plugin:
+        id: schema_plugin
+        options:
+          threshold: "not-an-int"
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with SLI plugin missing a required schema option should fail.": {
+			plugins: map[string]prometheus.SLIPlugin{
+				"schema_plugin": {
+					ID: "schema_plugin",
+					Func: func(ctx context.Context, meta map[string]string, labels map[string]string, options map[string]string) (string, error) {
+						return "plugin_raw_expr", nil
+					},
+					OptionsSchema: prometheus.SLIPluginOptionsSchema{
+						"threshold": {Kind: prometheus.SLIPluginOptionKindInt, Required: true},
+					},
+				},
+			},
+			specYaml: `
+service: test-svc
+version: "prometheus/v1"
+slos:
+  - name: "slo"
+    objective: 99
+    sli:
+      plugin:
+        id: schema_plugin
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expErr: true,
+		},
+
+		"Spec with SLI plugin options matching the plugin's schema should coerce typed values correctly.": {
+			plugins: map[string]prometheus.SLIPlugin{
+				"schema_plugin": {
+					ID: "schema_plugin",
+					Func: func(ctx context.Context, meta map[string]string, labels map[string]string, options map[string]string) (string, error) {
+						return fmt.Sprintf(`plugin_raw_expr{threshold="%s",ratio="%s",enabled="%s"}`,
+							options["threshold"], options["ratio"], options["enabled"]), nil
+					},
+					OptionsSchema: prometheus.SLIPluginOptionsSchema{
+						"threshold": {Kind: prometheus.SLIPluginOptionKindInt, Required: true},
+						"ratio":     {Kind: prometheus.SLIPluginOptionKindFloat},
+						"enabled":   {Kind: prometheus.SLIPluginOptionKindBool},
+					},
+				},
+			},
+			specYaml: `
+service: test-svc
+version: "prometheus/v1"
+slos:
+  - name: "slo-test"
+    objective: 99
+    sli:
+      plugin:
+        id: schema_plugin
+        options:
+          threshold: 5
+          ratio: 0.5
+          enabled: true
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo-test",
+					Name:       "slo-test",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{
+						Raw: &prometheus.SLIRaw{
+							ErrorRatioQuery: `plugin_raw_expr{threshold="5",ratio="0.5",enabled="true"}`,
+						},
+					},
+					Objective:       99,
+					PageAlertMeta:   prometheus.AlertMeta{Disable: true},
+					TicketAlertMeta: prometheus.AlertMeta{Disable: true},
+				},
+			}},
+		},
+
 		"Correct spec should return the models correctly.": {
 
 			specYaml: `
@@ -275,7 +464,7 @@ slos:
 		t.Run(name, func(t *testing.T) {
 			assert := assert.New(t)
 
-			loader := prometheus.NewYAMLSpecLoader(test.plugins)
+			loader := prometheus.NewYAMLSpecLoader(test.plugins, nil, nil)
 			gotModel, err := loader.LoadSpec(context.TODO(), []byte(test.specYaml))
 
 			if test.expErr {
@@ -0,0 +1,169 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"gopkg.in/yaml.v2"
+
+	prometheusv1 "github.com/slok/sloth/pkg/prometheus/api/v1"
+	prometheuspluginv1 "github.com/slok/sloth/pkg/prometheus/plugin/v1"
+)
+
+// yamlSpecV1Loader knows how to load prometheus/v1 YAML specs.
+type yamlSpecV1Loader struct {
+	plugins   map[string]SLIPlugin
+	pluginBus *SLIPluginEventBus
+	logger    log.Logger
+}
+
+func newYAMLSpecV1Loader(plugins map[string]SLIPlugin, pluginBus *SLIPluginEventBus, logger log.Logger) yamlSpecV1Loader {
+	return yamlSpecV1Loader{
+		plugins:   plugins,
+		pluginBus: pluginBus,
+		logger:    log.With(logger, "spec-version", prometheusv1.Version),
+	}
+}
+
+func (y yamlSpecV1Loader) LoadSpec(ctx context.Context, data []byte) (*SLOGroup, error) {
+	s := prometheusv1.Spec{}
+	err := yaml.Unmarshal(data, &s)
+	if err != nil {
+		level.Error(y.logger).Log("msg", "could not unmarshal YAML spec", "err", err)
+		return nil, fmt.Errorf("could not unmarshall YAML spec correctly: %w", err)
+	}
+
+	// Check at least we have one SLO.
+	if len(s.SLOs) == 0 {
+		level.Error(y.logger).Log("msg", "spec has no SLOs", "service", s.Service)
+		return nil, fmt.Errorf("at least one SLO is required")
+	}
+
+	m, err := y.mapSpecToModel(ctx, s)
+	if err != nil {
+		level.Error(y.logger).Log("msg", "could not map spec to model", "service", s.Service, "err", err)
+		return nil, fmt.Errorf("could not map to model: %w", err)
+	}
+
+	level.Info(y.logger).Log("msg", "spec loaded", "service", s.Service, "slos", len(m.SLOs))
+
+	return m, nil
+}
+
+func (y yamlSpecV1Loader) mapSpecToModel(ctx context.Context, spec prometheusv1.Spec) (*SLOGroup, error) {
+	models := make([]SLO, 0, len(spec.SLOs))
+	for _, specSLO := range spec.SLOs {
+		logger := log.With(y.logger, "service", spec.Service, "slo", specSLO.Name)
+		level.Debug(logger).Log("msg", "mapping SLO")
+
+		timeWindow, err := parseTimeWindow(specSLO.TimeWindow)
+		if err != nil {
+			level.Error(logger).Log("msg", "invalid SLO time window", "time_window", specSLO.TimeWindow, "err", err)
+			return nil, fmt.Errorf("invalid SLO %q time window: %w", specSLO.Name, err)
+		}
+
+		slo := SLO{
+			ID:              fmt.Sprintf("%s-%s", spec.Service, specSLO.Name),
+			Name:            specSLO.Name,
+			Description:     specSLO.Description,
+			Service:         spec.Service,
+			TimeWindow:      timeWindow,
+			Objective:       specSLO.Objective,
+			Labels:          mergeLabels(spec.Labels, specSLO.Labels),
+			PageAlertMeta:   AlertMeta{Disable: true},
+			TicketAlertMeta: AlertMeta{Disable: true},
+		}
+
+		// Set SLIs.
+		if specSLO.SLI.Events != nil {
+			slo.SLI.Events = &SLIEvents{
+				ErrorQuery: specSLO.SLI.Events.ErrorQuery,
+				TotalQuery: specSLO.SLI.Events.TotalQuery,
+			}
+		}
+
+		if specSLO.SLI.Raw != nil {
+			slo.SLI.Raw = &SLIRaw{
+				ErrorRatioQuery: specSLO.SLI.Raw.ErrorRatioQuery,
+			}
+		}
+
+		if specSLO.SLI.Plugin != nil {
+			pluginLogger := log.With(logger, "plugin", specSLO.SLI.Plugin.ID)
+
+			plugin, ok := y.plugins[specSLO.SLI.Plugin.ID]
+			if !ok {
+				level.Error(pluginLogger).Log("msg", "unknown SLI plugin")
+				return nil, fmt.Errorf("unknown plugin: %q", specSLO.SLI.Plugin.ID)
+			}
+
+			options, err := validatePluginOptions(specSLO.Name, plugin.ID, plugin.OptionsSchema, specSLO.SLI.Plugin.Options)
+			if err != nil {
+				level.Error(pluginLogger).Log("msg", "invalid SLI plugin options", "err", err)
+				return nil, fmt.Errorf("invalid plugin options: %w", err)
+			}
+
+			meta := map[string]string{
+				prometheuspluginv1.SLIPluginMetaService:   spec.Service,
+				prometheuspluginv1.SLIPluginMetaSLO:       specSLO.Name,
+				prometheuspluginv1.SLIPluginMetaObjective: fmt.Sprintf("%f", specSLO.Objective),
+			}
+
+			rawQuery, err := y.invokePlugin(ctx, pluginLogger, plugin, meta, spec.Labels, options)
+			if err != nil {
+				return nil, fmt.Errorf("plugin %q execution error: %w", specSLO.SLI.Plugin.ID, err)
+			}
+
+			slo.SLI.Raw = &SLIRaw{
+				ErrorRatioQuery: rawQuery,
+			}
+		}
+
+		// Set alerts.
+		if !specSLO.Alerting.PageAlert.Disable {
+			slo.PageAlertMeta = AlertMeta{
+				Name:        specSLO.Alerting.Name,
+				Labels:      mergeLabels(specSLO.Alerting.Labels, specSLO.Alerting.PageAlert.Labels),
+				Annotations: mergeLabels(specSLO.Alerting.Annotations, specSLO.Alerting.PageAlert.Annotations),
+			}
+		}
+
+		if !specSLO.Alerting.TicketAlert.Disable {
+			slo.TicketAlertMeta = AlertMeta{
+				Name:        specSLO.Alerting.Name,
+				Labels:      mergeLabels(specSLO.Alerting.Labels, specSLO.Alerting.TicketAlert.Labels),
+				Annotations: mergeLabels(specSLO.Alerting.Annotations, specSLO.Alerting.TicketAlert.Annotations),
+			}
+		}
+
+		models = append(models, slo)
+	}
+
+	return &SLOGroup{SLOs: models}, nil
+}
+
+// invokePlugin calls an SLI plugin, timing the call and publishing a
+// SLIPluginInvokedEvent regardless of the outcome.
+func (y yamlSpecV1Loader) invokePlugin(ctx context.Context, logger log.Logger, plugin SLIPlugin, meta, labels, options map[string]string) (string, error) {
+	start := time.Now()
+	rawQuery, err := plugin.Func(ctx, meta, labels, options)
+	duration := time.Since(start)
+
+	y.pluginBus.publishInvoked(SLIPluginInvokedEvent{
+		ID:         plugin.ID,
+		Meta:       meta,
+		DurationMS: duration.Milliseconds(),
+		Err:        err,
+	})
+
+	if err != nil {
+		level.Error(logger).Log("msg", "SLI plugin execution failed", "duration_ms", duration.Milliseconds(), "err", err)
+		return "", err
+	}
+	level.Info(logger).Log("msg", "SLI plugin invoked", "duration_ms", duration.Milliseconds())
+
+	return rawQuery, nil
+}
@@ -0,0 +1,209 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"gopkg.in/yaml.v2"
+
+	prometheusv2 "github.com/slok/sloth/pkg/prometheus/api/v2"
+	prometheuspluginv1 "github.com/slok/sloth/pkg/prometheus/plugin/v1"
+)
+
+// compositePlaceholderRegexp matches any `{{name}}` placeholder left over in
+// a composite SLI expression after substituting every configured plugin.
+// Only the exact `{{name}}` form is substituted, so a leftover is either a
+// placeholder that doesn't reference a known plugin key or a spaced one
+// such as `{{ name }}`.
+var compositePlaceholderRegexp = regexp.MustCompile(`{{\s*[^{}]+\s*}}`)
+
+// yamlSpecV2Loader knows how to load prometheus/v2 YAML specs.
+type yamlSpecV2Loader struct {
+	plugins   map[string]SLIPlugin
+	pluginBus *SLIPluginEventBus
+	logger    log.Logger
+}
+
+func newYAMLSpecV2Loader(plugins map[string]SLIPlugin, pluginBus *SLIPluginEventBus, logger log.Logger) yamlSpecV2Loader {
+	return yamlSpecV2Loader{
+		plugins:   plugins,
+		pluginBus: pluginBus,
+		logger:    log.With(logger, "spec-version", prometheusv2.Version),
+	}
+}
+
+func (y yamlSpecV2Loader) LoadSpec(ctx context.Context, data []byte) (*SLOGroup, error) {
+	s := prometheusv2.Spec{}
+	err := yaml.Unmarshal(data, &s)
+	if err != nil {
+		level.Error(y.logger).Log("msg", "could not unmarshal YAML spec", "err", err)
+		return nil, fmt.Errorf("could not unmarshall YAML spec correctly: %w", err)
+	}
+
+	// Check at least we have one SLO.
+	if len(s.SLOs) == 0 {
+		level.Error(y.logger).Log("msg", "spec has no SLOs", "service", s.Service)
+		return nil, fmt.Errorf("at least one SLO is required")
+	}
+
+	m, err := y.mapSpecToModel(ctx, s)
+	if err != nil {
+		level.Error(y.logger).Log("msg", "could not map spec to model", "service", s.Service, "err", err)
+		return nil, fmt.Errorf("could not map to model: %w", err)
+	}
+
+	level.Info(y.logger).Log("msg", "spec loaded", "service", s.Service, "slos", len(m.SLOs))
+
+	return m, nil
+}
+
+func (y yamlSpecV2Loader) mapSpecToModel(ctx context.Context, spec prometheusv2.Spec) (*SLOGroup, error) {
+	models := make([]SLO, 0, len(spec.SLOs))
+	for _, specSLO := range spec.SLOs {
+		logger := log.With(y.logger, "service", spec.Service, "slo", specSLO.Name)
+		level.Debug(logger).Log("msg", "mapping SLO")
+
+		timeWindow, err := parseTimeWindow(specSLO.TimeWindow)
+		if err != nil {
+			level.Error(logger).Log("msg", "invalid SLO time window", "time_window", specSLO.TimeWindow, "err", err)
+			return nil, fmt.Errorf("invalid SLO %q time window: %w", specSLO.Name, err)
+		}
+
+		slo := SLO{
+			ID:              fmt.Sprintf("%s-%s", spec.Service, specSLO.Name),
+			Name:            specSLO.Name,
+			Description:     specSLO.Description,
+			Service:         spec.Service,
+			TimeWindow:      timeWindow,
+			Objective:       specSLO.Objective,
+			Labels:          mergeLabels(spec.Labels, specSLO.Labels),
+			PageAlertMeta:   AlertMeta{Disable: true},
+			TicketAlertMeta: AlertMeta{Disable: true},
+		}
+
+		// Set SLIs.
+		if specSLO.SLI.Events != nil {
+			slo.SLI.Events = &SLIEvents{
+				ErrorQuery: specSLO.SLI.Events.ErrorQuery,
+				TotalQuery: specSLO.SLI.Events.TotalQuery,
+			}
+		}
+
+		if specSLO.SLI.Raw != nil {
+			slo.SLI.Raw = &SLIRaw{
+				ErrorRatioQuery: specSLO.SLI.Raw.ErrorRatioQuery,
+			}
+		}
+
+		if specSLO.SLI.Plugin != nil {
+			meta := map[string]string{
+				prometheuspluginv1.SLIPluginMetaService:   spec.Service,
+				prometheuspluginv1.SLIPluginMetaSLO:       specSLO.Name,
+				prometheuspluginv1.SLIPluginMetaObjective: fmt.Sprintf("%f", specSLO.Objective),
+			}
+
+			rawQuery, err := y.invokeNamedPlugin(ctx, logger, specSLO.Name, specSLO.SLI.Plugin.ID, meta, spec.Labels, specSLO.SLI.Plugin.Options)
+			if err != nil {
+				return nil, fmt.Errorf("plugin %q execution error: %w", specSLO.SLI.Plugin.ID, err)
+			}
+
+			slo.SLI.Raw = &SLIRaw{
+				ErrorRatioQuery: rawQuery,
+			}
+		}
+
+		if specSLO.SLI.Composite != nil {
+			rawQuery, err := y.resolveComposite(ctx, logger, spec, specSLO, *specSLO.SLI.Composite)
+			if err != nil {
+				return nil, fmt.Errorf("composite SLI error: %w", err)
+			}
+
+			slo.SLI.Raw = &SLIRaw{
+				ErrorRatioQuery: rawQuery,
+			}
+		}
+
+		// Set alerts, one route per configured severity.
+		slo.AlertRoutes = make([]AlertRoute, 0, len(specSLO.Alerting.Routes))
+		for _, route := range specSLO.Alerting.Routes {
+			slo.AlertRoutes = append(slo.AlertRoutes, AlertRoute{
+				Severity:    route.Severity,
+				Disable:     route.Disable,
+				Labels:      mergeLabels(specSLO.Alerting.Labels, route.Labels),
+				Annotations: mergeLabels(specSLO.Alerting.Annotations, route.Annotations),
+			})
+		}
+
+		models = append(models, slo)
+	}
+
+	return &SLOGroup{SLOs: models}, nil
+}
+
+// resolveComposite builds the final raw query of a composite SLI by
+// invoking every named plugin and substituting its raw query (parenthesised)
+// into the `{{name}}` placeholders of the composite expression.
+func (y yamlSpecV2Loader) resolveComposite(ctx context.Context, logger log.Logger, spec prometheusv2.Spec, specSLO prometheusv2.SLO, composite prometheusv2.SLIComposite) (string, error) {
+	expr := composite.Expr
+	for name, ref := range composite.Plugins {
+		meta := map[string]string{
+			prometheuspluginv1.SLIPluginMetaService:   spec.Service,
+			prometheuspluginv1.SLIPluginMetaSLO:       specSLO.Name,
+			prometheuspluginv1.SLIPluginMetaObjective: fmt.Sprintf("%f", specSLO.Objective),
+		}
+
+		rawQuery, err := y.invokeNamedPlugin(ctx, logger, specSLO.Name, ref.ID, meta, spec.Labels, ref.Options)
+		if err != nil {
+			return "", fmt.Errorf("plugin %q execution error: %w", ref.ID, err)
+		}
+
+		placeholder := fmt.Sprintf("{{%s}}", name)
+		expr = strings.ReplaceAll(expr, placeholder, fmt.Sprintf("(%s)", rawQuery))
+	}
+
+	if loc := compositePlaceholderRegexp.FindString(expr); loc != "" {
+		return "", fmt.Errorf("SLO %q composite SLI expression references unknown placeholder %q", specSLO.Name, loc)
+	}
+
+	return expr, nil
+}
+
+func (y yamlSpecV2Loader) invokeNamedPlugin(ctx context.Context, logger log.Logger, sloName, pluginID string, meta, labels map[string]string, rawOptions map[string]interface{}) (string, error) {
+	pluginLogger := log.With(logger, "plugin", pluginID)
+
+	plugin, ok := y.plugins[pluginID]
+	if !ok {
+		level.Error(pluginLogger).Log("msg", "unknown SLI plugin")
+		return "", fmt.Errorf("unknown plugin: %q", pluginID)
+	}
+
+	options, err := validatePluginOptions(sloName, plugin.ID, plugin.OptionsSchema, rawOptions)
+	if err != nil {
+		level.Error(pluginLogger).Log("msg", "invalid SLI plugin options", "err", err)
+		return "", fmt.Errorf("invalid plugin options: %w", err)
+	}
+
+	start := time.Now()
+	rawQuery, err := plugin.Func(ctx, meta, labels, options)
+	duration := time.Since(start)
+
+	y.pluginBus.publishInvoked(SLIPluginInvokedEvent{
+		ID:         pluginID,
+		Meta:       meta,
+		DurationMS: duration.Milliseconds(),
+		Err:        err,
+	})
+
+	if err != nil {
+		level.Error(pluginLogger).Log("msg", "SLI plugin execution failed", "duration_ms", duration.Milliseconds(), "err", err)
+		return "", err
+	}
+	level.Info(pluginLogger).Log("msg", "SLI plugin invoked", "duration_ms", duration.Milliseconds())
+
+	return rawQuery, nil
+}
@@ -0,0 +1,211 @@
+package prometheus_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/sloth/internal/prometheus"
+)
+
+func TestYAMLoadSpecV2(t *testing.T) {
+	tests := map[string]struct {
+		specYaml string
+		plugins  map[string]prometheus.SLIPlugin
+		expModel *prometheus.SLOGroup
+		expErr   bool
+	}{
+		"Spec without SLOs should fail.": {
+			specYaml: `
+service: test-svc
+version: "prometheus/v2"
+slos: []
+`,
+			expErr: true,
+		},
+
+		"Spec with an invalid time window should fail.": {
+			specYaml: `
+service: test-svc
+version: "prometheus/v2"
+slos:
+  - name: "slo"
+    objective: 99
+    time_window: "notaduration"
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio
+`,
+			expErr: true,
+		},
+
+		"Spec with multiple alert severities should map them to alert routes.": {
+			specYaml: `
+service: test-svc
+version: "prometheus/v2"
+slos:
+  - name: "slo"
+    objective: 99
+    sli:
+      raw:
+        error_ratio_query: test_expr_ratio
+    alerting:
+      name: testAlert
+      labels:
+        tier: "1"
+      routes:
+        - severity: page
+          labels:
+            channel: "#a-myteam"
+        - severity: ticket
+          disable: true
+        - severity: slack-low
+          annotations:
+            message: "fyi"
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo",
+					Name:       "slo",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{
+						Raw: &prometheus.SLIRaw{ErrorRatioQuery: "test_expr_ratio"},
+					},
+					Objective:       99,
+					PageAlertMeta:   prometheus.AlertMeta{Disable: true},
+					TicketAlertMeta: prometheus.AlertMeta{Disable: true},
+					AlertRoutes: []prometheus.AlertRoute{
+						{
+							Severity: "page",
+							Labels:   map[string]string{"tier": "1", "channel": "#a-myteam"},
+						},
+						{
+							Severity: "ticket",
+							Disable:  true,
+							Labels:   map[string]string{"tier": "1"},
+						},
+						{
+							Severity:    "slack-low",
+							Labels:      map[string]string{"tier": "1"},
+							Annotations: map[string]string{"message": "fyi"},
+						},
+					},
+				},
+			}},
+		},
+
+		"Spec with a composite SLI should combine the plugin outputs using the expression.": {
+			plugins: map[string]prometheus.SLIPlugin{
+				"bad_requests": {
+					ID: "bad_requests",
+					Func: func(ctx context.Context, meta, labels, options map[string]string) (string, error) {
+						return fmt.Sprintf("bad_requests_total{code=%q}", options["code"]), nil
+					},
+				},
+				"all_requests": {
+					ID: "all_requests",
+					Func: func(ctx context.Context, meta, labels, options map[string]string) (string, error) {
+						return "requests_total", nil
+					},
+				},
+			},
+			specYaml: `
+service: test-svc
+version: "prometheus/v2"
+slos:
+  - name: "slo"
+    objective: 99
+    sli:
+      composite:
+        expr: "{{errors}} / {{total}}"
+        plugins:
+          errors:
+            id: bad_requests
+            options:
+              code: "5.."
+          total:
+            id: all_requests
+`,
+			expModel: &prometheus.SLOGroup{SLOs: []prometheus.SLO{
+				{
+					ID:         "test-svc-slo",
+					Name:       "slo",
+					Service:    "test-svc",
+					TimeWindow: 30 * 24 * time.Hour,
+					SLI: prometheus.SLI{
+						Raw: &prometheus.SLIRaw{
+							ErrorRatioQuery: `(bad_requests_total{code="5.."}) / (requests_total)`,
+						},
+					},
+					Objective:       99,
+					PageAlertMeta:   prometheus.AlertMeta{Disable: true},
+					TicketAlertMeta: prometheus.AlertMeta{Disable: true},
+					AlertRoutes:     []prometheus.AlertRoute{},
+				},
+			}},
+		},
+
+		"Spec with a composite SLI referencing an unknown plugin should fail.": {
+			specYaml: `
+service: test-svc
+version: "prometheus/v2"
+slos:
+  - name: "slo"
+    objective: 99
+    sli:
+      composite:
+        expr: "{{errors}}"
+        plugins:
+          errors:
+            id: unknown_plugin
+`,
+			expErr: true,
+		},
+
+		"Spec with a composite SLI expression referencing a placeholder not in plugins should fail.": {
+			plugins: map[string]prometheus.SLIPlugin{
+				"bad_requests": {
+					ID: "bad_requests",
+					Func: func(ctx context.Context, meta, labels, options map[string]string) (string, error) {
+						return "bad_requests_total", nil
+					},
+				},
+			},
+			specYaml: `
+service: test-svc
+version: "prometheus/v2"
+slos:
+  - name: "slo"
+    objective: 99
+    sli:
+      composite:
+        expr: "{{errors}} / {{totall}}"
+        plugins:
+          errors:
+            id: bad_requests
+          total:
+            id: bad_requests
+`,
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			loader := prometheus.NewYAMLSpecLoader(test.plugins, nil, nil)
+			gotModel, err := loader.LoadSpec(context.TODO(), []byte(test.specYaml))
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expModel, gotModel)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Package v1 contains the API spec types used by sloth to load SLOs
+// declared using the Prometheus based spec format.
+package v1
+
+// Version is the spec version of this package.
+const Version = "prometheus/v1"
+
+// Spec represents the root of the YAML/JSON based spec used to declare
+// SLOs for a single service.
+type Spec struct {
+	Version string            `yaml:"version" json:"version"`
+	Service string            `yaml:"service" json:"service"`
+	Labels  map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	SLOs    []SLO             `yaml:"slos" json:"slos"`
+}
+
+// SLO is the spec to create an SLO.
+type SLO struct {
+	Name        string            `yaml:"name" json:"name"`
+	Description string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Objective   float64           `yaml:"objective" json:"objective"`
+	// TimeWindow is the rolling window used to compute the SLO (e.g "30d", "7d",
+	// "28d", "90d"). Defaults to "30d" when not set.
+	TimeWindow string   `yaml:"time_window,omitempty" json:"timeWindow,omitempty"`
+	SLI        SLI      `yaml:"sli" json:"sli"`
+	Alerting   Alerting `yaml:"alerting,omitempty" json:"alerting,omitempty"`
+}
+
+// SLI reprensents an SLI and how to calculate the SLI, every SLI
+// has a way it's calculated.
+type SLI struct {
+	Events *SLIEvents `yaml:"events,omitempty" json:"events,omitempty"`
+	Raw    *SLIRaw    `yaml:"raw,omitempty" json:"raw,omitempty"`
+	Plugin *SLIPlugin `yaml:"plugin,omitempty" json:"plugin,omitempty"`
+}
+
+// SLIEvents is the way to get the SLI (number of events) using raw queries.
+type SLIEvents struct {
+	ErrorQuery string `yaml:"error_query" json:"errorQuery"`
+	TotalQuery string `yaml:"total_query" json:"totalQuery"`
+}
+
+// SLIRaw is the way to get the SLI (percentage of failure) already calculated directly using a Prometheus query.
+type SLIRaw struct {
+	ErrorRatioQuery string `yaml:"error_ratio_query" json:"errorRatioQuery"`
+}
+
+// SLIPlugin is the way to get the SLI using an external SLI plugin that
+// generates the query to use based on the spec options. Options keep their
+// YAML-decoded type (string, bool, number) so plugins that declare an
+// OptionsSchema get typed values instead of everything being stringified.
+type SLIPlugin struct {
+	ID      string                 `yaml:"id" json:"id"`
+	Options map[string]interface{} `yaml:"options,omitempty" json:"options,omitempty"`
+}
+
+// Alerting wraps all the related SLO alert options.
+type Alerting struct {
+	Name        string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	PageAlert   Alert             `yaml:"page_alert,omitempty" json:"pageAlert,omitempty"`
+	TicketAlert Alert             `yaml:"ticket_alert,omitempty" json:"ticketAlert,omitempty"`
+}
+
+// Alert configures an alert severity route (e.g page, ticket).
+type Alert struct {
+	Disable     bool              `yaml:"disable,omitempty" json:"disable,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
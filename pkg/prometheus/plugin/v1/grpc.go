@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// jsonCodecName is registered as a grpc codec so the SLIPluginService can be
+// served and consumed without a protoc-generated protobuf codec, keeping the
+// plugin protocol easy to implement from any language that can speak JSON
+// over HTTP/2. The wire messages mirror plugin.proto.
+const jsonCodecName = "sloth-plugin-json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return jsonCodecName }
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// GenerateQueryRequest is the request of SLIPluginService.GenerateQuery.
+type GenerateQueryRequest struct {
+	Meta    map[string]string `json:"meta,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// GenerateQueryResponse is the response of SLIPluginService.GenerateQuery.
+type GenerateQueryResponse struct {
+	RawQuery string `json:"raw_query"`
+}
+
+// PingRequest is the request of SLIPluginService.Ping. It carries no
+// SLI-specific input on purpose, so liveness checks can't be rejected by
+// plugin-side business validation (e.g. required options).
+type PingRequest struct{}
+
+// PingResponse is the response of SLIPluginService.Ping.
+type PingResponse struct{}
+
+// SLIPluginServiceClient is the client API for SLIPluginService, matching
+// the RPCs declared in plugin.proto.
+type SLIPluginServiceClient interface {
+	GenerateQuery(ctx context.Context, in *GenerateQueryRequest, opts ...grpc.CallOption) (*GenerateQueryResponse, error)
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+}
+
+type sliPluginServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewSLIPluginServiceClient returns a client for SLIPluginService on top of
+// an already dialed connection.
+func NewSLIPluginServiceClient(cc grpc.ClientConnInterface) SLIPluginServiceClient {
+	return &sliPluginServiceClient{cc: cc}
+}
+
+func (c *sliPluginServiceClient) GenerateQuery(ctx context.Context, in *GenerateQueryRequest, opts ...grpc.CallOption) (*GenerateQueryResponse, error) {
+	out := new(GenerateQueryResponse)
+	opts = append(opts, grpc.CallContentSubtype(jsonCodecName))
+	err := c.cc.Invoke(ctx, sliPluginServiceGenerateQueryFullMethod, in, out, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SLIPluginService.GenerateQuery call failed: %w", err)
+	}
+
+	return out, nil
+}
+
+func (c *sliPluginServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	out := new(PingResponse)
+	opts = append(opts, grpc.CallContentSubtype(jsonCodecName))
+	err := c.cc.Invoke(ctx, sliPluginServicePingFullMethod, in, out, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SLIPluginService.Ping call failed: %w", err)
+	}
+
+	return out, nil
+}
+
+const sliPluginServiceGenerateQueryFullMethod = "/sloth.prometheus.plugin.v1.SLIPluginService/GenerateQuery"
+const sliPluginServicePingFullMethod = "/sloth.prometheus.plugin.v1.SLIPluginService/Ping"
+
+// SLIPluginServiceServer is the server API for SLIPluginService.
+type SLIPluginServiceServer interface {
+	GenerateQuery(context.Context, *GenerateQueryRequest) (*GenerateQueryResponse, error)
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+}
+
+// RegisterSLIPluginServiceServer registers srv on s so it serves the
+// SLIPluginService RPCs.
+func RegisterSLIPluginServiceServer(s grpc.ServiceRegistrar, srv SLIPluginServiceServer) {
+	s.RegisterService(&sliPluginServiceServiceDesc, srv)
+}
+
+func sliPluginServiceGenerateQueryHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(GenerateQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SLIPluginServiceServer).GenerateQuery(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: sliPluginServiceGenerateQueryFullMethod,
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(SLIPluginServiceServer).GenerateQuery(ctx, req.(*GenerateQueryRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func sliPluginServicePingHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SLIPluginServiceServer).Ping(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: sliPluginServicePingFullMethod,
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(SLIPluginServiceServer).Ping(ctx, req.(*PingRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+var sliPluginServiceServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sloth.prometheus.plugin.v1.SLIPluginService",
+	HandlerType: (*SLIPluginServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GenerateQuery",
+			Handler:    sliPluginServiceGenerateQueryHandler,
+		},
+		{
+			MethodName: "Ping",
+			Handler:    sliPluginServicePingHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
@@ -0,0 +1,12 @@
+// Package v1 contains the types used by SLI plugins to generate SLI queries:
+// the meta keys passed to every plugin and the SLIPluginService gRPC
+// protocol spoken by out-of-process plugins.
+package v1
+
+// Meta keys passed to SLI plugins so they can build their queries without
+// having to know about the sloth spec internals.
+const (
+	SLIPluginMetaService   = "service"
+	SLIPluginMetaSLO       = "slo"
+	SLIPluginMetaObjective = "objective"
+)